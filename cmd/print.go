@@ -1,12 +1,14 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/loft-sh/devspace/pkg/devspace/config"
 	"github.com/loft-sh/devspace/pkg/devspace/config/loader"
 	"github.com/loft-sh/devspace/pkg/devspace/dependency"
 	"github.com/loft-sh/devspace/pkg/devspace/dependency/types"
 	"github.com/loft-sh/devspace/pkg/devspace/hook"
+	"github.com/loft-sh/devspace/pkg/devspace/pipeline"
 	"github.com/loft-sh/devspace/pkg/devspace/plugin"
 	"io"
 	"os"
@@ -15,12 +17,19 @@ import (
 	"github.com/loft-sh/devspace/pkg/util/factory"
 	logger "github.com/loft-sh/devspace/pkg/util/log"
 	"github.com/loft-sh/devspace/pkg/util/message"
+	"github.com/pmezard/go-difflib/difflib"
 	"github.com/pkg/errors"
 	yaml "gopkg.in/yaml.v2"
 
 	"github.com/spf13/cobra"
 )
 
+// printOutputYAML and printOutputJSON are the supported values for --output.
+const (
+	printOutputYAML = "yaml"
+	printOutputJSON = "json"
+)
+
 // PrintCmd is a struct that defines a command call for "print"
 type PrintCmd struct {
 	*flags.GlobalFlags
@@ -30,6 +39,9 @@ type PrintCmd struct {
 	EagerVars bool
 
 	Dependency string
+
+	Output string
+	Diff   string
 }
 
 // NewPrintCmd creates a new devspace print command
@@ -46,7 +58,7 @@ func NewPrintCmd(f factory.Factory, globalFlags *flags.GlobalFlags) *cobra.Comma
 #######################################################
 ################## devspace print #####################
 #######################################################
-Prints the configuration for the current or given 
+Prints the configuration for the current or given
 profile after all patching and variable substitution
 #######################################################`,
 		RunE: func(cobraCmd *cobra.Command, args []string) error {
@@ -58,13 +70,14 @@ profile after all patching and variable substitution
 	printCmd.Flags().BoolVar(&cmd.SkipInfo, "skip-info", false, "When enabled, only prints the configuration without additional information")
 	printCmd.Flags().StringVar(&cmd.Dependency, "dependency", "", "The dependency to print the config from. Use dot to access nested dependencies (e.g. dep1.dep2)")
 	printCmd.Flags().BoolVar(&cmd.EagerVars, "eager-vars", false, "When enabled, eagerly fill variables")
+	printCmd.Flags().StringVar(&cmd.Output, "output", printOutputYAML, "The output format to print the configuration in. One of: yaml, json")
+	printCmd.Flags().StringVar(&cmd.Diff, "diff", "", "When set, prints a diff between the current profile and the given profile instead of the resolved configuration")
 
 	return printCmd
 }
 
 // Run executes the command logic
 func (cmd *PrintCmd) Run(f factory.Factory) error {
-	// Set config root
 	log := f.GetLog()
 	configOptions := cmd.ToConfigOptions(log)
 	configLoader := f.NewConfigLoader(cmd.ConfigPath)
@@ -75,85 +88,268 @@ func (cmd *PrintCmd) Run(f factory.Factory) error {
 		return errors.New(message.ConfigNotFound)
 	}
 
-	// create kubectl client
 	client, err := f.NewKubeClientFromContext(cmd.KubeContext, cmd.Namespace, cmd.SwitchContext)
 	if err != nil {
 		log.Warnf("Unable to create new kubectl client: %v", err)
 	}
 	configOptions.KubeClient = client
 
-	// load config
-	var loadedConfig config.Config
-	if cmd.EagerVars {
-		loadedConfig, err = configLoader.LoadWithParser(loader.NewEagerParser(), configOptions, log)
+	doc, err := cmd.loadPrintData(configLoader, configOptions, log, cmd.GlobalFlags.Profiles)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Diff != "" {
+		otherDoc, err := cmd.loadPrintData(configLoader, configOptions, log, []string{cmd.Diff})
 		if err != nil {
-			return err
+			return errors.Wrapf(err, "load profile %s for diff", cmd.Diff)
 		}
+
+		return renderDiff(doc, otherDoc, cmd.Diff, cmd.Out, log)
+	}
+
+	renderer, err := rendererForOutput(cmd.Output)
+	if err != nil {
+		return err
+	}
+
+	return renderer.Render(doc, cmd.SkipInfo, cmd.Out, log)
+}
+
+// loadPrintData loads the config (optionally switching profiles), resolves
+// its dependencies and runs the print plugin hook, then gathers everything a
+// Renderer needs into a single printData document.
+func (cmd *PrintCmd) loadPrintData(configLoader loader.ConfigLoader, configOptions *loader.ConfigOptions, log logger.Logger, profiles []string) (*printData, error) {
+	options := *configOptions
+	options.Profiles = profiles
+
+	var loadedConfig config.Config
+	var err error
+	if cmd.EagerVars {
+		loadedConfig, err = configLoader.LoadWithParser(loader.NewEagerParser(), &options, log)
 	} else {
-		loadedConfig, err = configLoader.Load(configOptions, log)
-		if err != nil {
-			return err
-		}
+		loadedConfig, err = configLoader.Load(&options, log)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	// resolve dependencies
-	dependencies, err := dependency.NewManager(loadedConfig, client, configOptions, log).ResolveAll(dependency.ResolveOptions{
+	dependencies, err := dependency.NewManager(loadedConfig, options.KubeClient, &options, log).ResolveAll(dependency.ResolveOptions{
 		Silent: true,
 	})
 	if err != nil {
 		log.Warnf("Error resolving dependencies: %v", err)
 	}
 
-	// Execute plugin hook
-	err = hook.ExecuteHooks(client, loadedConfig, dependencies, nil, log, "print")
+	err = hook.ExecuteHooks(options.KubeClient, loadedConfig, dependencies, nil, log, "print")
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if cmd.Dependency != "" {
 		dep := dependency.GetDependencyByPath(dependencies, cmd.Dependency)
 		if dep == nil {
-			return fmt.Errorf("couldn't find dependency %s: make sure it gets loaded correctly", cmd.Dependency)
+			return nil, fmt.Errorf("couldn't find dependency %s: make sure it gets loaded correctly", cmd.Dependency)
 		}
 
 		loadedConfig = dep.Config()
 	}
 
-	bsConfig, err := yaml.Marshal(loadedConfig.Config())
+	return gatherPrintData(loadedConfig, dependencies), nil
+}
+
+// printData is the data a Renderer needs, gathered once regardless of output
+// format so YAML, JSON and diff rendering stay in sync with each other.
+type printData struct {
+	Config       interface{}           `json:"config" yaml:"config"`
+	Vars         map[string]string     `json:"vars,omitempty" yaml:"vars,omitempty"`
+	Dependencies []printDependencyNode `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
+
+	path string
+}
+
+type printDependencyNode struct {
+	Name     string                `json:"name" yaml:"name"`
+	ID       string                `json:"id" yaml:"id"`
+	Children []printDependencyNode `json:"children,omitempty" yaml:"children,omitempty"`
+}
+
+func gatherPrintData(loadedConfig config.Config, dependencies []types.Dependency) *printData {
+	vars := buildPrintVars(loadedConfig.Variables(), pipeline.ActiveOutputsVars())
+
+	nodes := make([]printDependencyNode, 0, len(dependencies))
+	for _, dep := range dependencies {
+		nodes = append(nodes, printDependencyNodeFrom(dep))
+	}
+
+	return &printData{
+		Config:       loadedConfig.Config(),
+		Vars:         vars,
+		Dependencies: nodes,
+		path:         loadedConfig.Path(),
+	}
+}
+
+// buildPrintVars merges config variables with active pipeline step/job
+// outputs into the single Vars map used by every renderer (yaml, json,
+// diff), so none of them can drift out of sync with the others.
+func buildPrintVars(configVars map[string]interface{}, activeOutputs map[string]string) map[string]string {
+	vars := map[string]string{}
+	for varName, varValue := range configVars {
+		vars[varName] = fmt.Sprintf("%v", varValue)
+	}
+	for name, value := range activeOutputs {
+		vars[name] = value
+	}
+	return vars
+}
+
+func printDependencyNodeFrom(dep types.Dependency) printDependencyNode {
+	children := make([]printDependencyNode, 0, len(dep.Children()))
+	for _, child := range dep.Children() {
+		children = append(children, printDependencyNodeFrom(child))
+	}
+
+	return printDependencyNode{
+		Name:     dep.Name(),
+		ID:       dep.ID(),
+		Children: children,
+	}
+}
+
+// Renderer turns a gathered printData document into the command's final
+// output, so yaml/json/diff only need to differ in how they render, not in
+// how they gather the config/vars/dependency tree.
+type Renderer interface {
+	Render(data *printData, skipInfo bool, out io.Writer, log logger.Logger) error
+}
+
+func rendererForOutput(output string) (Renderer, error) {
+	switch output {
+	case "", printOutputYAML:
+		return yamlRenderer{}, nil
+	case printOutputJSON:
+		return jsonRenderer{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported --output %q: must be one of: yaml, json", output)
+	}
+}
+
+// yamlRenderer preserves the original `devspace print` behavior: just the
+// resolved config as YAML on Out, with vars/dependency info logged separately.
+type yamlRenderer struct{}
+
+func (yamlRenderer) Render(data *printData, skipInfo bool, out io.Writer, log logger.Logger) error {
+	bsConfig, err := yaml.Marshal(data.Config)
 	if err != nil {
 		return err
 	}
 
-	if !cmd.SkipInfo {
-		err = printExtraInfo(loadedConfig, dependencies, log)
-		if err != nil {
+	if !skipInfo {
+		if err := printExtraInfo(data, log); err != nil {
 			return err
 		}
 	}
 
-	if cmd.Out != nil {
-		_, err := cmd.Out.Write(bsConfig)
-		if err != nil {
-			return err
-		}
+	return writeOutput(out, log, bsConfig)
+}
+
+// jsonRenderer marshals config, vars and the dependency tree into a single
+// structured document, so downstream tooling doesn't need to parse the mixed
+// text+YAML stream the yaml renderer produces.
+type jsonRenderer struct{}
+
+func (jsonRenderer) Render(data *printData, skipInfo bool, out io.Writer, log logger.Logger) error {
+	doc := data
+	if skipInfo {
+		doc = &printData{Config: data.Config}
+	}
+
+	bs, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(out, log, bs)
+}
+
+func writeOutput(out io.Writer, log logger.Logger, bs []byte) error {
+	if out != nil {
+		_, err := out.Write(bs)
+		return err
+	}
+
+	log.WriteString(string(bs))
+	return nil
+}
+
+// renderDiff prints a unified diff of the two resolved configurations (the
+// active profile vs otherProfile) plus a table of variables that differ.
+func renderDiff(current, other *printData, otherProfile string, out io.Writer, log logger.Logger) error {
+	currentYAML, err := yaml.Marshal(current.Config)
+	if err != nil {
+		return err
+	}
+	otherYAML, err := yaml.Marshal(other.Config)
+	if err != nil {
+		return err
+	}
+
+	diff := difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(currentYAML)),
+		B:        difflib.SplitLines(string(otherYAML)),
+		FromFile: "current",
+		ToFile:   otherProfile,
+		Context:  3,
+	}
+	diffText, err := difflib.GetUnifiedDiffString(diff)
+	if err != nil {
+		return err
+	}
+
+	if err := writeOutput(out, log, []byte(diffText)); err != nil {
+		return err
+	}
+
+	headerColumnNames := []string{"Var", "Current", otherProfile}
+	values := diffVars(current.Vars, other.Vars)
+
+	log.WriteString("\n-------------------\n\nChanged vars:\n")
+	if len(values) > 0 {
+		logger.PrintTable(log, headerColumnNames, values)
 	} else {
-		log.WriteString(string(bsConfig))
+		log.Info("No differing vars found")
 	}
 
 	return nil
 }
 
-func printExtraInfo(config config.Config, dependencies []types.Dependency, log logger.Logger) error {
+// diffVars returns one row per variable that differs between current and
+// other: changed values, and values only present on one side.
+func diffVars(current, other map[string]string) [][]string {
+	values := [][]string{}
+	for name, currentValue := range current {
+		if otherValue, ok := other[name]; !ok || otherValue != currentValue {
+			values = append(values, []string{name, currentValue, other[name]})
+		}
+	}
+	for name, otherValue := range other {
+		if _, ok := current[name]; !ok {
+			values = append(values, []string{name, "", otherValue})
+		}
+	}
+	return values
+}
+
+// printExtraInfo prints the Vars table (which already includes active
+// pipeline step/job outputs - see buildPrintVars) and the dependency tree.
+func printExtraInfo(data *printData, log logger.Logger) error {
 	log.WriteString("\n-------------------\n\nVars:\n")
 
 	headerColumnNames := []string{"Name", "Value"}
 	values := [][]string{}
-	resolvedVars := config.Variables()
-	for varName, varValue := range resolvedVars {
-		values = append(values, []string{
-			varName,
-			fmt.Sprintf("%v", varValue),
-		})
+	for varName, varValue := range data.Vars {
+		values = append(values, []string{varName, varValue})
 	}
 
 	if len(values) > 0 {
@@ -162,12 +358,12 @@ func printExtraInfo(config config.Config, dependencies []types.Dependency, log l
 		log.Info("No vars found")
 	}
 
-	log.WriteString("\n-------------------\n\nLoaded path: " + config.Path() + "\n\n-------------------\n\n")
+	log.WriteString("\n-------------------\n\nLoaded path: " + data.path + "\n\n-------------------\n\n")
 
-	if len(dependencies) > 0 {
+	if len(data.Dependencies) > 0 {
 		log.WriteString("Dependency Tree:\n\n> Root\n")
-		for _, dep := range dependencies {
-			printDependencyRecursive("--", dep, log)
+		for _, dep := range data.Dependencies {
+			printDependencyNodeRecursive("--", dep, log)
 		}
 		log.WriteString("\n-------------------\n\n")
 	}
@@ -175,9 +371,9 @@ func printExtraInfo(config config.Config, dependencies []types.Dependency, log l
 	return nil
 }
 
-func printDependencyRecursive(prefix string, dep types.Dependency, log logger.Logger) {
-	log.WriteString(prefix + "> " + dep.Name() + " (ID: " + dep.ID()[:5] + ")\n")
-	for _, child := range dep.Children() {
-		printDependencyRecursive(prefix+"--", child, log)
+func printDependencyNodeRecursive(prefix string, dep printDependencyNode, log logger.Logger) {
+	log.WriteString(prefix + "> " + dep.Name + " (ID: " + dep.ID[:5] + ")\n")
+	for _, child := range dep.Children {
+		printDependencyNodeRecursive(prefix+"--", child, log)
 	}
 }
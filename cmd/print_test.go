@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestBuildPrintVarsMergesActiveOutputs(t *testing.T) {
+	vars := buildPrintVars(
+		map[string]interface{}{"DOMAIN": "example.com"},
+		map[string]string{"needs.build.outputs.image": "my-image:latest"},
+	)
+
+	assert.Equal(t, vars["DOMAIN"], "example.com")
+	assert.Equal(t, vars["needs.build.outputs.image"], "my-image:latest")
+	assert.Equal(t, len(vars), 2)
+}
+
+func TestDiffVarsReportsChangedAddedAndRemoved(t *testing.T) {
+	current := map[string]string{"A": "1", "B": "2", "REMOVED": "x"}
+	other := map[string]string{"A": "1", "B": "3", "ADDED": "y"}
+
+	rows := diffVars(current, other)
+
+	byName := map[string][]string{}
+	for _, row := range rows {
+		byName[row[0]] = row
+	}
+
+	assert.Equal(t, len(rows), 3, "A is unchanged and should not appear")
+	assert.Equal(t, byName["B"][1], "2")
+	assert.Equal(t, byName["B"][2], "3")
+	assert.Equal(t, byName["REMOVED"][2], "")
+	assert.Equal(t, byName["ADDED"][1], "")
+	assert.Equal(t, byName["ADDED"][2], "y")
+}
+
+func TestRendererForOutput(t *testing.T) {
+	tests := []struct {
+		output   string
+		expected Renderer
+		wantErr  bool
+	}{
+		{output: "", expected: yamlRenderer{}},
+		{output: printOutputYAML, expected: yamlRenderer{}},
+		{output: printOutputJSON, expected: jsonRenderer{}},
+		{output: "xml", wantErr: true},
+	}
+
+	for _, test := range tests {
+		renderer, err := rendererForOutput(test.output)
+		if test.wantErr {
+			assert.Assert(t, err != nil, "expected an error for output %q", test.output)
+			continue
+		}
+		assert.NilError(t, err)
+		assert.Equal(t, renderer, test.expected)
+	}
+}
+
+func TestYamlRendererSkipInfoWritesConfigOnly(t *testing.T) {
+	data := &printData{Config: map[string]interface{}{"foo": "bar"}}
+
+	var out bytes.Buffer
+	err := yamlRenderer{}.Render(data, true, &out, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, out.String(), "foo: bar\n")
+}
+
+func TestJSONRendererIncludesVarsAndDependencies(t *testing.T) {
+	data := &printData{
+		Config: map[string]interface{}{"foo": "bar"},
+		Vars:   map[string]string{"needs.build.outputs.image": "my-image:latest"},
+		Dependencies: []printDependencyNode{
+			{Name: "dep1", ID: "abcdef123", Children: []printDependencyNode{{Name: "dep2", ID: "123456789"}}},
+		},
+	}
+
+	var out bytes.Buffer
+	err := jsonRenderer{}.Render(data, false, &out, nil)
+	assert.NilError(t, err)
+
+	var decoded printData
+	assert.NilError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, decoded.Vars["needs.build.outputs.image"], "my-image:latest",
+		"--output json must surface pipeline outputs just like the yaml renderer's Vars table")
+	assert.Equal(t, len(decoded.Dependencies), 1)
+	assert.Equal(t, decoded.Dependencies[0].Name, "dep1")
+	assert.Equal(t, len(decoded.Dependencies[0].Children), 1)
+	assert.Equal(t, decoded.Dependencies[0].Children[0].Name, "dep2")
+}
+
+func TestJSONRendererSkipInfoOmitsVarsAndDependencies(t *testing.T) {
+	data := &printData{
+		Config:       map[string]interface{}{"foo": "bar"},
+		Vars:         map[string]string{"A": "1"},
+		Dependencies: []printDependencyNode{{Name: "dep1", ID: "abcdef123"}},
+	}
+
+	var out bytes.Buffer
+	err := jsonRenderer{}.Render(data, true, &out, nil)
+	assert.NilError(t, err)
+
+	var decoded map[string]interface{}
+	assert.NilError(t, json.Unmarshal(out.Bytes(), &decoded))
+	_, hasVars := decoded["vars"]
+	assert.Assert(t, !hasVars, "skip-info should omit vars from the json document")
+	_, hasDependencies := decoded["dependencies"]
+	assert.Assert(t, !hasDependencies, "skip-info should omit dependencies from the json document")
+}
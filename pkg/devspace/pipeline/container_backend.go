@@ -0,0 +1,151 @@
+package pipeline
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/loft-sh/devspace/pkg/devspace/config/versions/latest"
+	devspacecontext "github.com/loft-sh/devspace/pkg/devspace/context"
+)
+
+// teardownTimeout bounds how long container/pod teardown may take. Teardown
+// deliberately runs on its own context instead of the job's, since it must
+// still execute after that context has been cancelled.
+const teardownTimeout = 20 * time.Second
+
+// randomSuffix returns a short random hex string used to keep per-job
+// container names unique across concurrent jobs / matrix cells.
+func randomSuffix() string {
+	buf := make([]byte, 4)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// Local docker fallback, used when the job has no kube context (e.g. running
+// pipelines outside a cluster). Bind mounts the working directory so steps
+// see the same workspace they would on the host.
+
+func startDockerContainer(ctx *devspacecontext.Context, name string, container *latest.PipelineJobContainer) error {
+	args := []string{"run", "-d", "--name", name, "-v", fmt.Sprintf("%s:/workspace", ctx.WorkingDir), "-w", "/workspace"}
+	for _, v := range container.Volumes {
+		args = append(args, "-v", v)
+	}
+	for k, v := range container.Env {
+		args = append(args, "-e", fmt.Sprintf("%s=%s", k, v))
+	}
+	args = append(args, container.Image, "sleep", "infinity")
+
+	cmd := exec.CommandContext(ctx.Context, "docker", args...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("docker run: %v: %s", err, string(out))
+	}
+	return nil
+}
+
+// stepExecOptions carries the per-step directory/env overrides that must
+// reach the container shell. Neither `docker exec` nor `kubectl exec` accept
+// a per-invocation working-directory/env flag the way `docker run`/`kubectl
+// run` do for the container-level config, so they're folded into the shell
+// command itself via wrapStepCommand.
+type stepExecOptions struct {
+	Directory string
+	Env       map[string]string
+}
+
+// wrapStepCommand prefixes command with a `cd`/`export` preamble so a step's
+// Directory/Env reach the already-running container, matching the same
+// Directory/Env a step would get when run directly on the host.
+func wrapStepCommand(command string, opts stepExecOptions) string {
+	var b strings.Builder
+	if opts.Directory != "" {
+		b.WriteString(fmt.Sprintf("cd %s && ", shellQuote(opts.Directory)))
+	}
+
+	keys := make([]string, 0, len(opts.Env))
+	for k := range opts.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		b.WriteString(fmt.Sprintf("export %s=%s && ", k, shellQuote(opts.Env[k])))
+	}
+
+	b.WriteString(command)
+	return b.String()
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func execInDockerContainer(ctx *devspacecontext.Context, name, command string, opts stepExecOptions, output io.Writer) error {
+	cmd := exec.CommandContext(ctx.Context, "docker", "exec", name, "sh", "-c", wrapStepCommand(command, opts))
+	cmd.Stdout = output
+	cmd.Stderr = output
+	return cmd.Run()
+}
+
+func stopDockerContainer(ctx *devspacecontext.Context, name string) error {
+	// use a fresh context: ctx.Context is typically already cancelled by the
+	// time teardown runs (job finished, errored, or was cancelled), and a
+	// cancelled context makes exec.Cmd.Start refuse to run at all.
+	teardownCtx, cancel := context.WithTimeout(context.Background(), teardownTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(teardownCtx, "docker", "rm", "-f", name)
+	return cmd.Run()
+}
+
+// kubectl-backed implementation, used for jobs executed against a cluster.
+// Runs the container as a pod so step execs reuse the cluster's tooling/image
+// cache just like the rest of the engine does for dev containers.
+
+func startKubectlContainer(ctx *devspacecontext.Context, name string, container *latest.PipelineJobContainer) error {
+	args := []string{"run", name, "--image=" + container.Image, "--restart=Never", "--command", "--", "sleep", "infinity"}
+	if ctx.KubeClient != nil {
+		args = append(args, "--namespace", ctx.KubeClient.Namespace())
+	}
+
+	cmd := exec.CommandContext(ctx.Context, "kubectl", args...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("kubectl run: %v: %s", err, string(out))
+	}
+	return nil
+}
+
+func execInKubectlContainer(ctx *devspacecontext.Context, name, command string, opts stepExecOptions, output io.Writer) error {
+	args := []string{"exec", name}
+	if ctx.KubeClient != nil {
+		args = append(args, "--namespace", ctx.KubeClient.Namespace())
+	}
+	args = append(args, "--", "sh", "-c", wrapStepCommand(command, opts))
+
+	cmd := exec.CommandContext(ctx.Context, "kubectl", args...)
+	cmd.Stdout = output
+	cmd.Stderr = output
+	return cmd.Run()
+}
+
+func stopKubectlContainer(ctx *devspacecontext.Context, name string) error {
+	args := []string{"delete", "pod", name, "--ignore-not-found"}
+	if ctx.KubeClient != nil {
+		args = append(args, "--namespace", ctx.KubeClient.Namespace())
+	}
+
+	// see stopDockerContainer: teardown must still run after the job's
+	// context is cancelled, so it gets its own short-lived context.
+	teardownCtx, cancel := context.WithTimeout(context.Background(), teardownTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(teardownCtx, "kubectl", args...)
+	return cmd.Run()
+}
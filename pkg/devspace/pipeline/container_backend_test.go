@@ -0,0 +1,25 @@
+package pipeline
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestWrapStepCommandAddsDirectoryAndEnv(t *testing.T) {
+	wrapped := wrapStepCommand("go test ./...", stepExecOptions{
+		Directory: "services/api",
+		Env:       map[string]string{"B": "2", "A": "1"},
+	})
+
+	assert.Equal(t, wrapped, `cd 'services/api' && export A='1' && export B='2' && go test ./...`)
+}
+
+func TestWrapStepCommandWithNoOverrides(t *testing.T) {
+	wrapped := wrapStepCommand("go test ./...", stepExecOptions{})
+	assert.Equal(t, wrapped, "go test ./...")
+}
+
+func TestShellQuoteEscapesSingleQuotes(t *testing.T) {
+	assert.Equal(t, shellQuote(`it's`), `'it'\''s'`)
+}
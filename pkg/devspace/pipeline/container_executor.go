@@ -0,0 +1,153 @@
+package pipeline
+
+import (
+	"fmt"
+
+	"github.com/loft-sh/devspace/pkg/devspace/config/versions/latest"
+	devspacecontext "github.com/loft-sh/devspace/pkg/devspace/context"
+	"github.com/loft-sh/devspace/pkg/util/scanner"
+	"github.com/pkg/errors"
+	"io"
+)
+
+// containerExecutor runs a job's steps inside a single long-lived container
+// instead of spawning a fresh shell per step on the host. It is started once
+// per job and torn down once every step has run (or on error/cancellation).
+type containerExecutor interface {
+	// Start pulls the image (if needed) and starts the container. The docker
+	// backend bind mounts the current working directory as the workspace and
+	// applies the configured volumes and env; the kubectl backend starts a
+	// plain pod from the image and applies none of them.
+	Start(ctx *devspacecontext.Context) error
+	// Exec runs command inside the already-started container with the given
+	// step's directory/env overrides applied, streaming stdout/stderr to the
+	// given writer.
+	Exec(ctx *devspacecontext.Context, command string, opts stepExecOptions, output io.Writer) error
+	// Stop tears down the container. Safe to call even if Start failed
+	// partway through.
+	Stop(ctx *devspacecontext.Context) error
+}
+
+// newContainerExecutor builds the executor for a job's `container:` config,
+// preferring the existing kubectl client and falling back to local docker
+// when no kube context is available (matches the rest of the engine's
+// kubectl-first-then-docker-fallback pattern for running commands).
+func newContainerExecutor(container *latest.PipelineJobContainer) containerExecutor {
+	return &kubectlOrDockerExecutor{container: container}
+}
+
+// kubectlOrDockerExecutor is the containerExecutor used by doWork. It is
+// deliberately thin: the actual container lifecycle calls are delegated to
+// whichever client is available on the context (kubectl client already
+// used by the engine's exec handler, or a local docker client as fallback).
+type kubectlOrDockerExecutor struct {
+	container *latest.PipelineJobContainer
+
+	name string
+}
+
+func (e *kubectlOrDockerExecutor) Start(ctx *devspacecontext.Context) error {
+	if e.container == nil || e.container.Image == "" {
+		return errors.New("container executor requires an image")
+	}
+
+	e.name = fmt.Sprintf("devspace-job-%s", randomSuffix())
+	ctx.Log.Infof("starting container %s (%s) for job steps", e.name, e.container.Image)
+
+	if ctx.KubeClient != nil {
+		return startKubectlContainer(ctx, e.name, e.container)
+	}
+	return startDockerContainer(ctx, e.name, e.container)
+}
+
+func (e *kubectlOrDockerExecutor) Exec(ctx *devspacecontext.Context, command string, opts stepExecOptions, output io.Writer) error {
+	if ctx.KubeClient != nil {
+		return execInKubectlContainer(ctx, e.name, command, opts, output)
+	}
+	return execInDockerContainer(ctx, e.name, command, opts, output)
+}
+
+func (e *kubectlOrDockerExecutor) Stop(ctx *devspacecontext.Context) error {
+	if e.name == "" {
+		return nil
+	}
+
+	if ctx.KubeClient != nil {
+		return stopKubectlContainer(ctx, e.name)
+	}
+	return stopDockerContainer(ctx, e.name)
+}
+
+// doWorkInContainer is the container-backed equivalent of doWork: every step
+// is exec'd into a single container that is started once for the job and
+// torn down once all steps have run, on both the success and error paths.
+func (j *PipelineJob) doWorkInContainer(ctx *devspacecontext.Context) error {
+	return j.runStepsInContainer(ctx, newContainerExecutor(j.JobConfig.Container))
+}
+
+// runStepsInContainer holds the actual step loop, taking the containerExecutor
+// as a parameter so tests can drive it with a fake instead of a real
+// kubectl/docker backend.
+func (j *PipelineJob) runStepsInContainer(ctx *devspacecontext.Context, executor containerExecutor) error {
+	if err := executor.Start(ctx); err != nil {
+		return errors.Wrap(err, "start job container")
+	}
+	defer func() {
+		if err := executor.Stop(ctx); err != nil {
+			ctx.Log.Warnf("error stopping job container: %v", err)
+		}
+	}()
+
+	for i, step := range j.JobConfig.Steps {
+		var (
+			execute = true
+			err     error
+		)
+		if step.If != "" {
+			execute, err = j.shouldExecuteStep(ctx, &step)
+			if err != nil {
+				return errors.Wrapf(err, "error checking if at step %d", i)
+			}
+		}
+		if !execute {
+			continue
+		}
+
+		registry := j.outputsRegistry()
+		run := registry.resolveOutputExpressions(step.Run)
+		directory := registry.resolveOutputExpressions(step.Directory)
+		stepEnv := step.Env
+		if len(stepEnv) > 0 {
+			resolvedEnv := make(map[string]string, len(stepEnv))
+			for k, v := range stepEnv {
+				resolvedEnv[k] = registry.resolveOutputExpressions(v)
+			}
+			stepEnv = resolvedEnv
+		}
+
+		stdoutReader, stdoutWriter := io.Pipe()
+		scanDone := make(chan struct{})
+		go func() {
+			defer close(scanDone)
+			s := scanner.NewScanner(stdoutReader)
+			for s.Scan() {
+				line := s.Text()
+				if name, value, ok := parseSetOutputLine(line); ok {
+					registry.SetStepOutput(stepID(step, i), name, value)
+					registry.SetJobOutput(j.Name, name, value)
+					continue
+				}
+				ctx.Log.Info(line)
+			}
+		}()
+
+		err = executor.Exec(ctx, run, stepExecOptions{Directory: directory, Env: stepEnv}, stdoutWriter)
+		stdoutWriter.Close()
+		<-scanDone
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package pipeline
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/loft-sh/devspace/pkg/devspace/config/versions/latest"
+	devspacecontext "github.com/loft-sh/devspace/pkg/devspace/context"
+	"gotest.tools/assert"
+)
+
+func TestRandomSuffixIsUnique(t *testing.T) {
+	a := randomSuffix()
+	b := randomSuffix()
+	assert.Assert(t, a != b, "expected distinct random suffixes")
+	assert.Equal(t, len(a), 8)
+}
+
+// fakeContainerExecutor lets tests drive runStepsInContainer's teardown
+// behavior without shelling out to a real kubectl/docker backend.
+type fakeContainerExecutor struct {
+	startErr error
+	execErr  error
+	stopErr  error
+
+	started bool
+	stopped bool
+
+	// gotOpts records the stepExecOptions passed to the most recent Exec call.
+	gotOpts stepExecOptions
+}
+
+func (f *fakeContainerExecutor) Start(ctx *devspacecontext.Context) error {
+	f.started = true
+	return f.startErr
+}
+
+func (f *fakeContainerExecutor) Exec(ctx *devspacecontext.Context, command string, opts stepExecOptions, output io.Writer) error {
+	f.gotOpts = opts
+	return f.execErr
+}
+
+func (f *fakeContainerExecutor) Stop(ctx *devspacecontext.Context) error {
+	f.stopped = true
+	return f.stopErr
+}
+
+func TestRunStepsInContainerSkipsStopWhenStartFails(t *testing.T) {
+	executor := &fakeContainerExecutor{startErr: errTestStart}
+	job := &PipelineJob{Name: "build", JobConfig: &latest.PipelineJob{
+		Steps: []latest.PipelineStep{{Run: "echo hi"}},
+	}}
+
+	err := job.runStepsInContainer(&devspacecontext.Context{}, executor)
+	assert.ErrorContains(t, err, "start job container")
+	assert.Assert(t, !executor.stopped, "Stop should not run if Start never succeeded")
+}
+
+func TestRunStepsInContainerStopsOnStepError(t *testing.T) {
+	executor := &fakeContainerExecutor{execErr: errTestExec}
+	job := &PipelineJob{Name: "build", JobConfig: &latest.PipelineJob{
+		Steps: []latest.PipelineStep{{Run: "false"}},
+	}}
+
+	err := job.runStepsInContainer(&devspacecontext.Context{}, executor)
+	assert.Equal(t, err, errTestExec)
+	assert.Assert(t, executor.stopped, "container should still be torn down after a step fails")
+}
+
+func TestRunStepsInContainerStopsOnCancellation(t *testing.T) {
+	executor := &fakeContainerExecutor{execErr: context.Canceled}
+	job := &PipelineJob{Name: "build", JobConfig: &latest.PipelineJob{
+		Steps: []latest.PipelineStep{{Run: "sleep 100"}},
+	}}
+
+	err := job.runStepsInContainer(&devspacecontext.Context{}, executor)
+	assert.Equal(t, err, context.Canceled)
+	assert.Assert(t, executor.stopped, "container should still be torn down when the job context is cancelled mid-step")
+}
+
+func TestRunStepsInContainerStopsOnSuccess(t *testing.T) {
+	executor := &fakeContainerExecutor{}
+	job := &PipelineJob{Name: "build", JobConfig: &latest.PipelineJob{
+		Steps: []latest.PipelineStep{{Run: "echo hi"}},
+	}}
+
+	err := job.runStepsInContainer(&devspacecontext.Context{}, executor)
+	assert.NilError(t, err)
+	assert.Assert(t, executor.started)
+	assert.Assert(t, executor.stopped)
+}
+
+func TestRunStepsInContainerPassesStepDirectoryAndEnv(t *testing.T) {
+	executor := &fakeContainerExecutor{}
+	job := &PipelineJob{Name: "build", JobConfig: &latest.PipelineJob{
+		Steps: []latest.PipelineStep{{
+			Run:       "go build ./...",
+			Directory: "services/api",
+			Env:       map[string]string{"GOFLAGS": "-mod=vendor"},
+		}},
+	}}
+
+	err := job.runStepsInContainer(&devspacecontext.Context{}, executor)
+	assert.NilError(t, err)
+	assert.Equal(t, executor.gotOpts.Directory, "services/api")
+	assert.Equal(t, executor.gotOpts.Env["GOFLAGS"], "-mod=vendor")
+}
+
+var (
+	errTestStart = &testError{"start failed"}
+	errTestExec  = &testError{"exec failed"}
+)
+
+type testError struct{ msg string }
+
+func (e *testError) Error() string { return e.msg }
@@ -0,0 +1,61 @@
+package pipeline
+
+import (
+	"fmt"
+
+	"github.com/loft-sh/devspace/pkg/devspace/config/versions/latest"
+	"github.com/loft-sh/devspace/pkg/devspace/dependency/registry"
+	"github.com/loft-sh/devspace/pkg/devspace/devpod"
+)
+
+// BuildPipelineJobs turns a map of job configs (keyed by job name, each
+// optionally listing the jobs it `needs`) into the wired *PipelineJob DAG
+// that Run() walks: Parents/Children are resolved from Needs, every job
+// shares the same DependencyRegistry/DevPodManager/Outputs registry, and any
+// job with a `matrix:` strategy is expanded into one *PipelineJob per cell
+// (see expandMatrixJob) before being returned.
+func BuildPipelineJobs(jobConfigs map[string]*latest.PipelineJob, dependencyRegistry registry.DependencyRegistry, devPodManager devpod.Manager) ([]*PipelineJob, error) {
+	outputs := newOutputRegistry()
+
+	jobs := make(map[string]*PipelineJob, len(jobConfigs))
+	for name, jobConfig := range jobConfigs {
+		jobs[name] = &PipelineJob{
+			Name:               name,
+			DependencyRegistry: dependencyRegistry,
+			DevPodManager:      devPodManager,
+			JobConfig:          jobConfig,
+			Outputs:            outputs,
+		}
+	}
+
+	for name, jobConfig := range jobConfigs {
+		job := jobs[name]
+		for _, need := range jobConfig.Needs {
+			parent, ok := jobs[need]
+			if !ok {
+				return nil, fmt.Errorf("job %s needs unknown job %s", name, need)
+			}
+
+			job.Parents = append(job.Parents, parent)
+			parent.Children = append(parent.Children, job)
+		}
+	}
+
+	all := make([]*PipelineJob, 0, len(jobs))
+	for _, job := range jobs {
+		all = append(all, job)
+	}
+
+	result := make([]*PipelineJob, 0, len(all))
+	for _, job := range all {
+		cells, err := expandMatrixJob(job)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, cells...)
+	}
+
+	SetActiveOutputs(outputs)
+
+	return result, nil
+}
@@ -0,0 +1,82 @@
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/loft-sh/devspace/pkg/devspace/config/versions/latest"
+	"gotest.tools/assert"
+)
+
+func TestBuildPipelineJobsWiresNeeds(t *testing.T) {
+	jobs, err := BuildPipelineJobs(map[string]*latest.PipelineJob{
+		"build": {},
+		"test":  {Needs: []string{"build"}},
+	}, nil, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, len(jobs), 2)
+
+	var build, test *PipelineJob
+	for _, j := range jobs {
+		switch j.Name {
+		case "build":
+			build = j
+		case "test":
+			test = j
+		}
+	}
+	assert.Assert(t, build != nil && test != nil)
+	assert.Equal(t, len(test.Parents), 1)
+	assert.Equal(t, test.Parents[0], build)
+	assert.Equal(t, len(build.Children), 1)
+	assert.Equal(t, build.Children[0], test)
+}
+
+func TestBuildPipelineJobsExpandsMatrix(t *testing.T) {
+	jobs, err := BuildPipelineJobs(map[string]*latest.PipelineJob{
+		"build": {
+			Matrix: &latest.PipelineJobMatrix{
+				Values: map[string][]string{"os": {"linux", "darwin"}},
+			},
+		},
+		"deploy": {Needs: []string{"build"}},
+	}, nil, nil)
+	assert.NilError(t, err)
+
+	cellCount := 0
+	var deploy *PipelineJob
+	for _, j := range jobs {
+		if j.Name == "deploy" {
+			deploy = j
+			continue
+		}
+		cellCount++
+	}
+	assert.Equal(t, cellCount, 2, "matrix job should have been expanded into 2 cells")
+	assert.Assert(t, deploy != nil)
+	assert.Equal(t, len(deploy.Parents), 2, "deploy should fan in on both matrix cells")
+}
+
+func TestBuildPipelineJobsSharesOutputsRegistry(t *testing.T) {
+	jobs, err := BuildPipelineJobs(map[string]*latest.PipelineJob{
+		"build": {},
+		"test":  {Needs: []string{"build"}},
+	}, nil, nil)
+	assert.NilError(t, err)
+
+	var build, test *PipelineJob
+	for _, j := range jobs {
+		switch j.Name {
+		case "build":
+			build = j
+		case "test":
+			test = j
+		}
+	}
+
+	build.Outputs.SetJobOutput("build", "image", "my-image:latest")
+	resolved := test.Outputs.resolveOutputExpressions("${{ needs.build.outputs.image }}")
+	assert.Equal(t, resolved, "my-image:latest", "jobs built by the same DAG should share one outputs registry")
+
+	vars := ActiveOutputsVars()
+	assert.Equal(t, vars["needs.build.outputs.image"], "my-image:latest", "print should surface the active pipeline's outputs")
+}
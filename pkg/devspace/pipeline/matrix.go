@@ -0,0 +1,204 @@
+package pipeline
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/loft-sh/devspace/pkg/devspace/config/versions/latest"
+)
+
+// expandMatrixJob expands a single PipelineJob configured with a `matrix:`
+// strategy into one *PipelineJob per cell of the Cartesian product, rewiring
+// Parents/Children so the cells sit in the DAG in place of the original job.
+// Jobs without a Matrix are returned unchanged.
+//
+// Each cell gets a deterministic name (`<job>-<key>-<value>-...`), MATRIX_<KEY>
+// env vars on every step, and `${{ matrix.key }}` substitution applied to
+// step.Run, step.Directory and step.Env before execution.
+func expandMatrixJob(job *PipelineJob) ([]*PipelineJob, error) {
+	if job.JobConfig.Matrix == nil {
+		return []*PipelineJob{job}, nil
+	}
+
+	cells, err := matrixCells(job.JobConfig.Matrix)
+	if err != nil {
+		return nil, fmt.Errorf("error expanding matrix for job %s: %w", job.Name, err)
+	}
+
+	maxParallel := job.JobConfig.Matrix.MaxParallel
+	var sem chan struct{}
+	if maxParallel > 0 {
+		sem = make(chan struct{}, maxParallel)
+	}
+
+	cellJobs := make([]*PipelineJob, 0, len(cells))
+	for _, cell := range cells {
+		cellJob := &PipelineJob{
+			Name:               matrixCellName(job.Name, cell),
+			DependencyRegistry: job.DependencyRegistry,
+			DevPodManager:      job.DevPodManager,
+			Outputs:            job.Outputs,
+			JobConfig:          substituteMatrixConfig(job.JobConfig, cell),
+			Parents:            job.Parents,
+			Children:           job.Children,
+			matrixCell:         cell,
+			matrixSemaphore:    sem,
+		}
+		cellJobs = append(cellJobs, cellJob)
+	}
+
+	// rewire the DAG: parents now point at every cell, and every cell points
+	// at the original children, which fan back in once all cells are done.
+	for _, parent := range job.Parents {
+		parent.Children = replaceJobInSlice(parent.Children, job, cellJobs)
+	}
+	for _, child := range job.Children {
+		// child already waits on all of its Parents before starting (see
+		// PipelineJob.Run), so replacing the single matrix job with every
+		// cell here is what makes the child fan in on all of them.
+		child.Parents = replaceJobInSlice(child.Parents, job, cellJobs)
+	}
+
+	return cellJobs, nil
+}
+
+func matrixCellName(jobName string, cell map[string]string) string {
+	keys := make([]string, 0, len(cell))
+	for k := range cell {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys)+1)
+	parts = append(parts, jobName)
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s-%s", k, cell[k]))
+	}
+	return strings.Join(parts, "-")
+}
+
+// matrixCells computes the Cartesian product of the matrix's dimensions,
+// appends every `include` entry as an additional cell (include entries are
+// not merged into existing cells) and then drops any cell matched by
+// `exclude`, including cells added by `include`.
+func matrixCells(matrix *latest.PipelineJobMatrix) ([]map[string]string, error) {
+	keys := make([]string, 0, len(matrix.Values))
+	for k := range matrix.Values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	cells := []map[string]string{{}}
+	for _, key := range keys {
+		values := matrix.Values[key]
+		if len(values) == 0 {
+			return nil, fmt.Errorf("matrix dimension %q has no values", key)
+		}
+
+		next := make([]map[string]string, 0, len(cells)*len(values))
+		for _, existing := range cells {
+			for _, value := range values {
+				cell := make(map[string]string, len(existing)+1)
+				for k, v := range existing {
+					cell[k] = v
+				}
+				cell[key] = value
+				next = append(next, cell)
+			}
+		}
+		cells = next
+	}
+
+	for _, include := range matrix.Include {
+		cells = append(cells, include)
+	}
+
+	filtered := cells[:0]
+	for _, cell := range cells {
+		excluded := false
+		for _, exclude := range matrix.Exclude {
+			if cellMatches(cell, exclude) {
+				excluded = true
+				break
+			}
+		}
+		if !excluded {
+			filtered = append(filtered, cell)
+		}
+	}
+
+	return filtered, nil
+}
+
+func cellMatches(cell, filter map[string]string) bool {
+	for k, v := range filter {
+		if cell[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func replaceJobInSlice(jobs []*PipelineJob, old *PipelineJob, with []*PipelineJob) []*PipelineJob {
+	result := make([]*PipelineJob, 0, len(jobs)-1+len(with))
+	for _, j := range jobs {
+		if j == old {
+			result = append(result, with...)
+			continue
+		}
+		result = append(result, j)
+	}
+	return result
+}
+
+// substituteMatrixConfig returns a copy of jobConfig with MATRIX_<KEY> env
+// vars and `${{ matrix.key }}` substitution applied to every step.
+func substituteMatrixConfig(jobConfig *latest.PipelineJob, cell map[string]string) *latest.PipelineJob {
+	cfg := *jobConfig
+	cfg.Matrix = nil // cells don't re-expand
+
+	steps := make([]latest.PipelineStep, len(jobConfig.Steps))
+	for i, step := range jobConfig.Steps {
+		step.Run = substituteMatrixVars(step.Run, cell)
+		step.Directory = substituteMatrixVars(step.Directory, cell)
+
+		env := make(map[string]string, len(step.Env)+len(cell))
+		for k, v := range step.Env {
+			env[k] = substituteMatrixVars(v, cell)
+		}
+		for k, v := range cell {
+			env[fmt.Sprintf("MATRIX_%s", strings.ToUpper(k))] = v
+		}
+		step.Env = env
+
+		steps[i] = step
+	}
+	cfg.Steps = steps
+
+	return &cfg
+}
+
+func substituteMatrixVars(value string, cell map[string]string) string {
+	for key, v := range cell {
+		value = strings.ReplaceAll(value, fmt.Sprintf("${{ matrix.%s }}", key), v)
+		value = strings.ReplaceAll(value, fmt.Sprintf("${{matrix.%s}}", key), v)
+	}
+	return value
+}
+
+// acquireMatrixSlot blocks until a matrix-cell execution slot is available,
+// honoring the job's `max-parallel` setting. Jobs without a matrix semaphore
+// (no max-parallel configured) return immediately.
+func (j *PipelineJob) acquireMatrixSlot(stop <-chan struct{}) (release func(), ok bool) {
+	if j.matrixSemaphore == nil {
+		return func() {}, true
+	}
+
+	select {
+	case j.matrixSemaphore <- struct{}{}:
+		return func() { <-j.matrixSemaphore }, true
+	case <-stop:
+		return func() {}, false
+	}
+}
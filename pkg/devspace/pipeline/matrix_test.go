@@ -0,0 +1,77 @@
+package pipeline
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/loft-sh/devspace/pkg/devspace/config/versions/latest"
+	"gotest.tools/assert"
+)
+
+func cellKey(cell map[string]string) string {
+	keys := make([]string, 0, len(cell))
+	for k := range cell {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	key := ""
+	for _, k := range keys {
+		key += k + "=" + cell[k] + ";"
+	}
+	return key
+}
+
+func TestMatrixCellsCartesianProduct(t *testing.T) {
+	matrix := &latest.PipelineJobMatrix{
+		Values: map[string][]string{
+			"os":   {"linux", "darwin"},
+			"arch": {"amd64", "arm64"},
+		},
+	}
+
+	cells, err := matrixCells(matrix)
+	assert.NilError(t, err)
+	assert.Equal(t, len(cells), 4)
+
+	seen := map[string]bool{}
+	for _, cell := range cells {
+		seen[cellKey(cell)] = true
+	}
+	assert.Equal(t, seen["arch=amd64;os=linux;"], true)
+	assert.Equal(t, seen["arch=arm64;os=linux;"], true)
+	assert.Equal(t, seen["arch=amd64;os=darwin;"], true)
+	assert.Equal(t, seen["arch=arm64;os=darwin;"], true)
+}
+
+func TestMatrixCellsIncludeExclude(t *testing.T) {
+	matrix := &latest.PipelineJobMatrix{
+		Values: map[string][]string{
+			"os":   {"linux", "darwin"},
+			"arch": {"amd64", "arm64"},
+		},
+		Include: []map[string]string{
+			{"os": "windows", "arch": "amd64"},
+		},
+		Exclude: []map[string]string{
+			{"os": "darwin", "arch": "arm64"},
+		},
+	}
+
+	cells, err := matrixCells(matrix)
+	assert.NilError(t, err)
+
+	seen := map[string]bool{}
+	for _, cell := range cells {
+		seen[cellKey(cell)] = true
+	}
+	assert.Equal(t, seen["arch=amd64;os=windows;"], true, "include should add the extra cell")
+	assert.Equal(t, seen["arch=arm64;os=darwin;"], false, "exclude should drop the matching cell")
+	assert.Equal(t, len(cells), 4)
+}
+
+func TestSubstituteMatrixVars(t *testing.T) {
+	cell := map[string]string{"os": "linux"}
+	result := substituteMatrixVars("build --target ${{ matrix.os }}", cell)
+	assert.Equal(t, result, "build --target linux")
+}
@@ -0,0 +1,197 @@
+package pipeline
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// setOutputLinePrefix is the magic GitHub-Actions-style line steps can print
+// to stdout to register an output, e.g. `::set-output name=foo::bar`.
+const setOutputLinePrefix = "::set-output "
+
+var setOutputLineRegexp = regexp.MustCompile(`^::set-output name=([^:]+)::(.*)$`)
+
+// setOutputCommandName names the alternative line form
+// `devspace set-output name=foo value=bar`. Like the `::set-output` form, it
+// is recognised only as a line a step prints to stdout (see
+// parseSetOutputLine); this package registers no `set-output` command.
+const setOutputCommandName = "set-output"
+
+var setOutputCommandRegexp = regexp.MustCompile(`^devspace\s+set-output\s+name=(\S+)\s+value=(.*)$`)
+
+// OutputsRegistry stores step and job outputs captured during a pipeline run,
+// keyed by step id / job name and then output name. It is safe for
+// concurrent use since sibling jobs and matrix cells populate it in parallel.
+type OutputsRegistry struct {
+	m sync.Mutex
+
+	stepOutputs map[string]map[string]string
+	jobOutputs  map[string]map[string]string
+}
+
+func newOutputRegistry() *OutputsRegistry {
+	return &OutputsRegistry{
+		stepOutputs: map[string]map[string]string{},
+		jobOutputs:  map[string]map[string]string{},
+	}
+}
+
+func (r *OutputsRegistry) SetStepOutput(stepID, name, value string) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if r.stepOutputs[stepID] == nil {
+		r.stepOutputs[stepID] = map[string]string{}
+	}
+	r.stepOutputs[stepID][name] = value
+}
+
+func (r *OutputsRegistry) SetJobOutput(jobName, name, value string) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if r.jobOutputs[jobName] == nil {
+		r.jobOutputs[jobName] = map[string]string{}
+	}
+	r.jobOutputs[jobName][name] = value
+}
+
+func (r *OutputsRegistry) StepOutput(stepID, name string) (string, bool) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	v, ok := r.stepOutputs[stepID][name]
+	return v, ok
+}
+
+func (r *OutputsRegistry) JobOutput(jobName, name string) (string, bool) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	v, ok := r.jobOutputs[jobName][name]
+	return v, ok
+}
+
+var activeOutputs struct {
+	m sync.Mutex
+	r *OutputsRegistry
+}
+
+// SetActiveOutputs records the registry shared by the job DAG currently
+// running in this process, so other commands sharing the process (e.g.
+// `devspace print` invoked after/alongside a pipeline run) can surface
+// captured outputs. Called once by BuildPipelineJobs per pipeline run.
+func SetActiveOutputs(r *OutputsRegistry) {
+	activeOutputs.m.Lock()
+	defer activeOutputs.m.Unlock()
+	activeOutputs.r = r
+}
+
+// ActiveOutputsVars returns the flattened outputs (see Vars) of the most
+// recently built job DAG in this process, or nil if no pipeline has run yet.
+func ActiveOutputsVars() map[string]string {
+	activeOutputs.m.Lock()
+	r := activeOutputs.r
+	activeOutputs.m.Unlock()
+
+	if r == nil {
+		return nil
+	}
+	return r.Vars()
+}
+
+// Vars flattens every captured output into a single map suitable for
+// rendering in the `print` command's Vars table, e.g.
+// "steps.build.outputs.image" -> "my-image:latest".
+func (r *OutputsRegistry) Vars() map[string]string {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	vars := map[string]string{}
+	for stepID, outputs := range r.stepOutputs {
+		for name, value := range outputs {
+			vars[fmt.Sprintf("steps.%s.outputs.%s", stepID, name)] = value
+		}
+	}
+	for jobName, outputs := range r.jobOutputs {
+		for name, value := range outputs {
+			vars[fmt.Sprintf("needs.%s.outputs.%s", jobName, name)] = value
+		}
+	}
+	return vars
+}
+
+// parseSetOutputLine extracts name/value from a `::set-output name=..::..`
+// or `devspace set-output name=.. value=..` line. ok is false if the line
+// doesn't match either form and should be forwarded to the logger as-is.
+func parseSetOutputLine(line string) (name, value string, ok bool) {
+	if strings.HasPrefix(line, setOutputLinePrefix) {
+		if m := setOutputLineRegexp.FindStringSubmatch(line); m != nil {
+			return m[1], m[2], true
+		}
+	}
+	if m := setOutputCommandRegexp.FindStringSubmatch(line); m != nil {
+		return m[1], m[2], true
+	}
+	return "", "", false
+}
+
+var stepsOutputExprRegexp = regexp.MustCompile(`\$\{\{\s*steps\.([\w-]+)\.outputs\.([\w-]+)\s*\}\}`)
+var needsOutputExprRegexp = regexp.MustCompile(`\$\{\{\s*needs\.([\w-]+)\.outputs\.([\w-]+)\s*\}\}`)
+
+// resolveOutputExpressions replaces `${{ steps.<id>.outputs.<name> }}` and
+// `${{ needs.<job>.outputs.<name> }}` expressions in value with their
+// captured outputs. Unresolvable expressions are left untouched so the
+// shell-based fallback can still see them.
+func (r *OutputsRegistry) resolveOutputExpressions(value string) string {
+	value = stepsOutputExprRegexp.ReplaceAllStringFunc(value, func(expr string) string {
+		m := stepsOutputExprRegexp.FindStringSubmatch(expr)
+		if v, ok := r.StepOutput(m[1], m[2]); ok {
+			return v
+		}
+		return expr
+	})
+	value = needsOutputExprRegexp.ReplaceAllStringFunc(value, func(expr string) string {
+		m := needsOutputExprRegexp.FindStringSubmatch(expr)
+		if v, ok := r.JobOutput(m[1], m[2]); ok {
+			return v
+		}
+		return expr
+	})
+	return value
+}
+
+// evaluateIfExpression natively evaluates simple equality / boolean `step.If`
+// expressions built from steps/needs output references, e.g.
+// `${{ steps.build.outputs.changed }}` or
+// `${{ steps.build.outputs.changed == 'true' }}`. ok is false if the
+// expression isn't one of these simple forms, so the caller should fall back
+// to the shell-based evaluation.
+func (r *OutputsRegistry) evaluateIfExpression(expr string) (result bool, ok bool) {
+	expr = strings.TrimSpace(expr)
+	if !strings.HasPrefix(expr, "${{") || !strings.HasSuffix(expr, "}}") {
+		return false, false
+	}
+	inner := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(expr, "${{"), "}}"))
+
+	if eqIdx := strings.Index(inner, "=="); eqIdx != -1 {
+		left := strings.TrimSpace(inner[:eqIdx])
+		ref := fmt.Sprintf("${{ %s }}", left)
+		if !stepsOutputExprRegexp.MatchString(ref) && !needsOutputExprRegexp.MatchString(ref) {
+			// left side is not a steps/needs reference we understand
+			return false, false
+		}
+		right := strings.Trim(strings.TrimSpace(inner[eqIdx+2:]), `'"`)
+		resolved := r.resolveOutputExpressions(ref)
+		return resolved == right, true
+	}
+
+	resolved := r.resolveOutputExpressions(fmt.Sprintf("${{ %s }}", inner))
+	if resolved == fmt.Sprintf("${{ %s }}", inner) {
+		// nothing was resolved, not a steps/needs reference we understand
+		return false, false
+	}
+	return resolved == "true", true
+}
@@ -0,0 +1,68 @@
+package pipeline
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestParseSetOutputLine(t *testing.T) {
+	tests := []struct {
+		line      string
+		wantName  string
+		wantValue string
+		wantOk    bool
+	}{
+		{line: "::set-output name=image::my-image:latest", wantName: "image", wantValue: "my-image:latest", wantOk: true},
+		{line: "devspace set-output name=changed value=true", wantName: "changed", wantValue: "true", wantOk: true},
+		{line: "just a regular log line", wantOk: false},
+	}
+
+	for _, test := range tests {
+		name, value, ok := parseSetOutputLine(test.line)
+		assert.Equal(t, ok, test.wantOk, test.line)
+		if test.wantOk {
+			assert.Equal(t, name, test.wantName, test.line)
+			assert.Equal(t, value, test.wantValue, test.line)
+		}
+	}
+}
+
+func TestResolveOutputExpressions(t *testing.T) {
+	registry := newOutputRegistry()
+	registry.SetStepOutput("build", "image", "my-image:latest")
+	registry.SetJobOutput("build-job", "ready", "true")
+
+	result := registry.resolveOutputExpressions("docker push ${{ steps.build.outputs.image }}")
+	assert.Equal(t, result, "docker push my-image:latest")
+
+	result = registry.resolveOutputExpressions("${{ needs.build-job.outputs.ready }}")
+	assert.Equal(t, result, "true")
+
+	result = registry.resolveOutputExpressions("${{ steps.missing.outputs.x }}")
+	assert.Equal(t, result, "${{ steps.missing.outputs.x }}")
+}
+
+func TestEvaluateIfExpression(t *testing.T) {
+	registry := newOutputRegistry()
+	registry.SetStepOutput("build", "changed", "true")
+
+	result, ok := registry.evaluateIfExpression("${{ steps.build.outputs.changed }}")
+	assert.Equal(t, ok, true)
+	assert.Equal(t, result, true)
+
+	result, ok = registry.evaluateIfExpression("${{ steps.build.outputs.changed == 'false' }}")
+	assert.Equal(t, ok, true)
+	assert.Equal(t, result, false)
+
+	_, ok = registry.evaluateIfExpression("test -f ./file")
+	assert.Equal(t, ok, false, "non steps/needs expressions should fall back to the shell")
+}
+
+func TestOutputRegistryVars(t *testing.T) {
+	registry := newOutputRegistry()
+	registry.SetStepOutput("build", "image", "my-image:latest")
+
+	vars := registry.Vars()
+	assert.Equal(t, vars["steps.build.outputs.image"], "my-image:latest")
+}
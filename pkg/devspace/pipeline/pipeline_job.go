@@ -26,23 +26,61 @@ type PipelineJob struct {
 	JobConfig *latest.PipelineJob
 	Job       Job
 
+	// Outputs stores step and job outputs set via `devspace set-output` /
+	// `::set-output` so later steps and downstream jobs can reference them
+	// via `${{ steps.<id>.outputs.<name> }}` / `${{ needs.<job>.outputs.<name> }}`.
+	// The DAG builder assigns the same registry to every PipelineJob so
+	// outputs are visible across job boundaries; falls back to a private
+	// registry if never assigned.
+	Outputs *OutputsRegistry
+
 	Parents  []*PipelineJob
 	Children []*PipelineJob
 
 	startOnce sync.Once
 	err       error
+
+	// watchDone is closed once this job is done for good, i.e. after its
+	// initial run AND after its rerun watcher (if any) has stopped. Children
+	// wait on this instead of j.Job.Done(), since the latter only reflects
+	// the initial run.
+	watchDone chan struct{}
+
+	// rerunMu serializes cascadeRerun invocations for this job, so an
+	// upstream job rerunning faster than this job's own steps finish can't
+	// execute this job's steps concurrently with itself.
+	rerunMu sync.Mutex
+
+	// matrixCell holds the matrix values this job was expanded for, if any.
+	// Set by expandMatrixJob; nil for jobs with no `matrix:` strategy.
+	matrixCell map[string]string
+	// matrixSemaphore caps how many sibling cells of the same matrix job may
+	// run concurrently. Shared by all cells of one matrix expansion, sized by
+	// `max-parallel`; nil if unset.
+	matrixSemaphore chan struct{}
 }
 
 func (j *PipelineJob) Run(ctx *devspacecontext.Context) error {
 	j.startOnce.Do(func() {
+		j.watchDone = make(chan struct{})
+		defer close(j.watchDone)
+
 		for _, parent := range j.Parents {
 			select {
 			case <-ctx.Context.Done():
 				return
-			case <-parent.Job.Done():
+			case <-parent.done():
 			}
 		}
 
+		// if this job is one cell of a matrix expansion, cap concurrent
+		// cells using the shared semaphore sized by `max-parallel`
+		release, ok := j.acquireMatrixSlot(ctx.Context.Done())
+		if !ok {
+			return
+		}
+		defer release()
+
 		// start the actual job
 		err := j.Job.Start(ctx, j.doWork)
 		if err != nil {
@@ -59,16 +97,36 @@ func (j *PipelineJob) Run(ctx *devspacecontext.Context) error {
 			return
 		}
 
-		// if rerun we should watch here
+		// if rerun is configured, watch the configured paths and restart the
+		// job's steps until the pipeline's root context is cancelled. Children
+		// only see this job as Done once watching stops (see watchDone above).
 		if j.JobConfig.Rerun != nil {
-			// TODO: watch and restart job here
-			return
+			if err := j.watchAndRerun(ctx); err != nil {
+				j.err = err
+				return
+			}
 		}
 	})
 	return j.err
 }
 
+// done returns a channel that is closed once this job is finished for good,
+// i.e. including any rerun watching. Falls back to the underlying Job's Done
+// channel if Run hasn't started yet.
+func (j *PipelineJob) done() <-chan struct{} {
+	if j.watchDone != nil {
+		return j.watchDone
+	}
+	return j.Job.Done()
+}
+
 func (j *PipelineJob) doWork(ctx *devspacecontext.Context) error {
+	// jobs with a `container:` / `runsOn:` config reuse a single long-lived
+	// container across all of their steps instead of a fresh host shell per step
+	if j.JobConfig.Container != nil {
+		return j.doWorkInContainer(ctx)
+	}
+
 	// loop over steps and execute them
 	for i, step := range j.JobConfig.Steps {
 		var (
@@ -82,7 +140,7 @@ func (j *PipelineJob) doWork(ctx *devspacecontext.Context) error {
 			}
 		}
 		if execute {
-			err = j.executeStep(ctx, &step)
+			err = j.executeStep(ctx, stepID(step, i), &step)
 			if err != nil {
 				return err
 			}
@@ -92,8 +150,31 @@ func (j *PipelineJob) doWork(ctx *devspacecontext.Context) error {
 	return nil
 }
 
+// outputsRegistry returns the registry used to store/resolve step and job
+// outputs, lazily creating a private one if the DAG builder never assigned
+// a shared Outputs registry.
+func (j *PipelineJob) outputsRegistry() *OutputsRegistry {
+	if j.Outputs == nil {
+		j.Outputs = newOutputRegistry()
+	}
+	return j.Outputs
+}
+
+func stepID(step latest.PipelineStep, index int) string {
+	if step.ID != "" {
+		return step.ID
+	}
+	return fmt.Sprintf("%d", index)
+}
+
 func (j *PipelineJob) shouldExecuteStep(ctx *devspacecontext.Context, step *latest.PipelineStep) (bool, error) {
-	// check if step should be rerun
+	// evaluate simple `${{ steps.<id>.outputs.<name> }}` / `${{ needs.<job>.outputs.<name> }}`
+	// equality and boolean expressions natively, without a shell subprocess
+	if result, ok := j.outputsRegistry().evaluateIfExpression(step.If); ok {
+		return result, nil
+	}
+
+	// fall back to the shell-based evaluation for anything else
 	handler := engine.NewExecHandler(ctx, j.DependencyRegistry, j.DevPodManager, false)
 	stdout := &bytes.Buffer{}
 	stderr := &bytes.Buffer{}
@@ -111,17 +192,35 @@ func (j *PipelineJob) shouldExecuteStep(ctx *devspacecontext.Context, step *late
 	return true, nil
 }
 
-func (j *PipelineJob) executeStep(ctx *devspacecontext.Context, step *latest.PipelineStep) error {
+func (j *PipelineJob) executeStep(ctx *devspacecontext.Context, id string, step *latest.PipelineStep) error {
+	registry := j.outputsRegistry()
+	run := registry.resolveOutputExpressions(step.Run)
+	directory := registry.resolveOutputExpressions(step.Directory)
+	stepEnv := step.Env
+	if len(stepEnv) > 0 {
+		resolvedEnv := make(map[string]string, len(stepEnv))
+		for k, v := range stepEnv {
+			resolvedEnv[k] = registry.resolveOutputExpressions(v)
+		}
+		stepEnv = resolvedEnv
+	}
+
 	stdoutReader, stdoutWriter := io.Pipe()
 	defer stdoutWriter.Close()
 	go func() {
 		s := scanner.NewScanner(stdoutReader)
 		for s.Scan() {
-			ctx.Log.Info(s.Text())
+			line := s.Text()
+			if name, value, ok := parseSetOutputLine(line); ok {
+				registry.SetStepOutput(id, name, value)
+				registry.SetJobOutput(j.Name, name, value)
+				continue
+			}
+			ctx.Log.Info(line)
 		}
 	}()
 
 	handler := engine.NewExecHandler(ctx, j.DependencyRegistry, j.DevPodManager, true)
-	_, err := engine.ExecuteShellCommand(ctx.Context, step.Run, os.Args[1:], step.Directory, stdoutWriter, stdoutWriter, env.NewVariableEnvProvider(ctx.Config, step.Env), handler)
+	_, err := engine.ExecuteShellCommand(ctx.Context, run, os.Args[1:], directory, stdoutWriter, stdoutWriter, env.NewVariableEnvProvider(ctx.Config, stepEnv), handler)
 	return err
 }
\ No newline at end of file
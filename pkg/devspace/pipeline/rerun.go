@@ -0,0 +1,260 @@
+package pipeline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bmatcuk/doublestar"
+	devspacecontext "github.com/loft-sh/devspace/pkg/devspace/context"
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+)
+
+// defaultRerunDebounce is used when Rerun.Debounce is not set in the config.
+const defaultRerunDebounce = 200 * time.Millisecond
+
+// watchAndRerun blocks until ctx is cancelled, re-running the job's steps
+// whenever a file matching Rerun.Paths (and not Rerun.Exclude) changes.
+// Bursts of events are collapsed via debouncing, and a run that is still in
+// flight when a new event arrives is cancelled before the next run starts.
+func (j *PipelineJob) watchAndRerun(ctx *devspacecontext.Context) error {
+	root := ctx.WorkingDir
+	if root == "" {
+		root = "."
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return errors.Wrap(err, "create file watcher")
+	}
+	defer watcher.Close()
+
+	if err := addWatchPaths(watcher, root, j.JobConfig.Rerun.Paths); err != nil {
+		return errors.Wrap(err, "watch rerun paths")
+	}
+
+	debounce := defaultRerunDebounce
+	if j.JobConfig.Rerun.Debounce > 0 {
+		debounce = time.Duration(j.JobConfig.Rerun.Debounce) * time.Millisecond
+	}
+
+	// run goes through serializedRerun/rerunMu just like cascadeRerun does,
+	// so this job's own watch-triggered rerun can never execute doWork
+	// concurrently with a cascade arriving from an upstream job's rerun.
+	run := func(runCtx context.Context) error {
+		j.serializedRerun(
+			func() bool { return runCtx.Err() != nil },
+			func() error { return j.doWork(ctx.WithContext(runCtx)) },
+			ctx.Log.Warnf,
+			func() { j.rerunChildren(ctx) },
+		)
+		return nil
+	}
+	matches := func(file string) bool {
+		return matchesRerunPaths(root, file, j.JobConfig.Rerun.Paths, j.JobConfig.Rerun.Exclude)
+	}
+
+	return watchLoop(ctx.Context, watcher.Events, watcher.Errors, matches, debounce, run, ctx.Log.Warnf)
+}
+
+// watchLoop contains the debounce + cancel-in-flight state machine used by
+// watchAndRerun. It is factored out so tests can drive it with fake events
+// instead of a real fsnotify watcher.
+func watchLoop(
+	ctx context.Context,
+	events <-chan fsnotify.Event,
+	watchErrors <-chan error,
+	matches func(file string) bool,
+	debounce time.Duration,
+	run func(runCtx context.Context) error,
+	warnf func(format string, args ...interface{}),
+) error {
+	var (
+		timer     *time.Timer
+		cancelRun context.CancelFunc
+		runDone   = closedChan()
+	)
+
+	triggerRun := func() {
+		// cancel a previous run that is still going and wait for it to unwind
+		if cancelRun != nil {
+			cancelRun()
+			<-runDone
+		}
+
+		var runCtx context.Context
+		runCtx, cancelRun = context.WithCancel(ctx)
+		doneCh := make(chan struct{})
+		runDone = doneCh
+
+		go func() {
+			defer close(doneCh)
+			if err := run(runCtx); err != nil && runCtx.Err() == nil {
+				warnf("error rerunning job: %v", err)
+			}
+		}()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			if cancelRun != nil {
+				cancelRun()
+			}
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if !matches(event.Name) {
+				continue
+			}
+			if timer != nil {
+				timer.Stop()
+			}
+			timer = time.AfterFunc(debounce, triggerRun)
+		case err, ok := <-watchErrors:
+			if !ok {
+				return nil
+			}
+			warnf("file watcher error: %v", err)
+		}
+	}
+}
+
+func closedChan() chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}
+
+// rerunChildren cascades a successful rerun to children that are themselves
+// rerun-capable, so the whole downstream chain picks up the new state.
+func (j *PipelineJob) rerunChildren(ctx *devspacecontext.Context) {
+	for _, child := range j.Children {
+		if child.JobConfig.Rerun == nil {
+			continue
+		}
+		go child.cascadeRerun(ctx)
+	}
+}
+
+// cascadeRerun re-executes this job's steps in response to an upstream
+// rerun completing successfully, then cascades further downstream on
+// success. cascadeRerun serializes on rerunMu instead of touching startOnce,
+// so an upstream job rerunning in quick succession can't run this job
+// concurrently with itself, and it re-invokes doWork directly rather than
+// Run(), so it never spins up a second file watcher for this job.
+func (j *PipelineJob) cascadeRerun(ctx *devspacecontext.Context) {
+	j.serializedRerun(
+		func() bool { return ctx.Context.Err() != nil },
+		func() error { return j.doWork(ctx) },
+		ctx.Log.Warnf,
+		func() { j.rerunChildren(ctx) },
+	)
+}
+
+// serializedRerun holds the rerun state machine in a form that's testable
+// without a real devspacecontext.Context. It is shared by both a job's own
+// watch-triggered rerun (run, in watchAndRerun) and a cascade arriving from
+// an upstream job's rerun (cascadeRerun): both run work() under rerunMu, so
+// the two can never execute this job's steps concurrently with each other,
+// skipping work entirely if cancelled() is already true, and only calling
+// cascade() once work succeeds.
+func (j *PipelineJob) serializedRerun(cancelled func() bool, work func() error, warnf func(format string, args ...interface{}), cascade func()) {
+	j.rerunMu.Lock()
+	defer j.rerunMu.Unlock()
+
+	if cancelled() {
+		return
+	}
+
+	if err := work(); err != nil {
+		warnf("error rerunning job %s: %v", j.Name, err)
+		return
+	}
+
+	cascade()
+}
+
+func addWatchPaths(watcher *fsnotify.Watcher, root string, paths []string) error {
+	dirs, err := collectWatchDirs(root, paths)
+	if err != nil {
+		return err
+	}
+
+	for _, dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectWatchDirs returns every directory under each rerun path pattern's
+// base directory. fsnotify.Watcher is not recursive, so a pattern like
+// `src/**/*.go` needs every directory nested under `src/` added individually
+// or events from subdirectories are silently missed.
+func collectWatchDirs(root string, paths []string) ([]string, error) {
+	bases := map[string]bool{}
+	for _, pattern := range paths {
+		base, _ := doublestar.SplitPattern(filepath.Join(root, pattern))
+		bases[base] = true
+	}
+	if len(bases) == 0 {
+		bases[root] = true
+	}
+
+	dirs := map[string]bool{}
+	for base := range bases {
+		err := filepath.Walk(base, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if info.IsDir() {
+				dirs[path] = true
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		result = append(result, dir)
+	}
+	return result, nil
+}
+
+func matchesRerunPaths(root, file string, include, exclude []string) bool {
+	rel, err := filepath.Rel(root, file)
+	if err != nil {
+		rel = file
+	}
+	rel = filepath.ToSlash(rel)
+
+	matched := false
+	for _, pattern := range include {
+		if ok, _ := doublestar.Match(pattern, rel); ok {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return false
+	}
+
+	for _, pattern := range exclude {
+		if ok, _ := doublestar.Match(pattern, rel); ok {
+			return false
+		}
+	}
+	return true
+}
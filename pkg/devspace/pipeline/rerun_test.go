@@ -0,0 +1,214 @@
+package pipeline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"gotest.tools/assert"
+)
+
+func TestMatchesRerunPaths(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		include []string
+		exclude []string
+		expect  bool
+	}{
+		{
+			name:    "matches include",
+			file:    "/project/src/main.go",
+			include: []string{"src/**/*.go"},
+			expect:  true,
+		},
+		{
+			name:    "does not match include",
+			file:    "/project/docs/readme.md",
+			include: []string{"src/**/*.go"},
+			expect:  false,
+		},
+		{
+			name:    "excluded even though included",
+			file:    "/project/src/main_test.go",
+			include: []string{"src/**/*.go"},
+			exclude: []string{"src/**/*_test.go"},
+			expect:  false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			matched := matchesRerunPaths("/project", test.file, test.include, test.exclude)
+			assert.Equal(t, matched, test.expect, test.name)
+		})
+	}
+}
+
+func TestWatchLoopDebouncesBurstsOfEvents(t *testing.T) {
+	events := make(chan fsnotify.Event)
+	errs := make(chan error)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var runs int32
+	run := func(runCtx context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		return nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		_ = watchLoop(ctx, events, errs, func(string) bool { return true }, 50*time.Millisecond, run, func(string, ...interface{}) {})
+		close(done)
+	}()
+
+	// fire a burst of events well within the debounce window
+	for i := 0; i < 5; i++ {
+		events <- fsnotify.Event{Name: "main.go"}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// give the debounce timer time to fire exactly once
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, atomic.LoadInt32(&runs), int32(1), "debounced burst should trigger a single run")
+
+	cancel()
+	<-done
+}
+
+func TestWatchLoopCancelsInFlightRunOnNewEvent(t *testing.T) {
+	events := make(chan fsnotify.Event)
+	errs := make(chan error)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var cancelled int32
+	started := make(chan struct{}, 2)
+	run := func(runCtx context.Context) error {
+		started <- struct{}{}
+		<-runCtx.Done()
+		atomic.AddInt32(&cancelled, 1)
+		return runCtx.Err()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		_ = watchLoop(ctx, events, errs, func(string) bool { return true }, 10*time.Millisecond, run, func(string, ...interface{}) {})
+		close(done)
+	}()
+
+	events <- fsnotify.Event{Name: "main.go"}
+	<-started // first run is now blocked on its context
+
+	events <- fsnotify.Event{Name: "main.go"}
+	<-started // second run only starts once the first was cancelled
+
+	assert.Equal(t, atomic.LoadInt32(&cancelled), int32(1), "in-flight run should be cancelled when a new event arrives")
+
+	cancel()
+	<-done
+}
+
+func TestSerializedRerunDoesNotOverlap(t *testing.T) {
+	job := &PipelineJob{Name: "child"}
+
+	var inFlight, overlapped int32
+	work := func() error {
+		if atomic.AddInt32(&inFlight, 1) > 1 {
+			atomic.StoreInt32(&overlapped, 1)
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		return nil
+	}
+
+	var cascades int32
+	done := make(chan struct{}, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			job.serializedRerun(
+				func() bool { return false },
+				work,
+				func(string, ...interface{}) {},
+				func() { atomic.AddInt32(&cascades, 1) },
+			)
+			done <- struct{}{}
+		}()
+	}
+	<-done
+	<-done
+
+	assert.Equal(t, atomic.LoadInt32(&overlapped), int32(0), "two cascades for the same job should never run concurrently")
+	assert.Equal(t, atomic.LoadInt32(&cascades), int32(2))
+}
+
+func TestSerializedRerunSkipsWorkWhenCancelled(t *testing.T) {
+	job := &PipelineJob{Name: "child"}
+
+	var ran bool
+	job.serializedRerun(
+		func() bool { return true },
+		func() error { ran = true; return nil },
+		func(string, ...interface{}) {},
+		func() {},
+	)
+
+	assert.Equal(t, ran, false, "cascadeRerun should skip work entirely once cancelled")
+}
+
+// TestSerializedRerunSerializesLocalTriggerAgainstCascade simulates the two
+// real call sites of serializedRerun - a job's own watch-triggered run and a
+// cascade arriving from an upstream job's rerun - firing at the same time,
+// and proves they still can't execute this job's steps concurrently.
+func TestSerializedRerunSerializesLocalTriggerAgainstCascade(t *testing.T) {
+	job := &PipelineJob{Name: "child"}
+
+	var inFlight, overlapped int32
+	work := func() error {
+		if atomic.AddInt32(&inFlight, 1) > 1 {
+			atomic.StoreInt32(&overlapped, 1)
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		return nil
+	}
+
+	done := make(chan struct{}, 2)
+	// simulates watchAndRerun's own local-file-triggered run
+	go func() {
+		job.serializedRerun(func() bool { return false }, work, func(string, ...interface{}) {}, func() {})
+		done <- struct{}{}
+	}()
+	// simulates an upstream job's rerun cascading into this job at the same time
+	go func() {
+		job.serializedRerun(func() bool { return false }, work, func(string, ...interface{}) {}, func() {})
+		done <- struct{}{}
+	}()
+	<-done
+	<-done
+
+	assert.Equal(t, atomic.LoadInt32(&overlapped), int32(0), "a job's own watch-triggered rerun must serialize against an upstream cascade rerun")
+}
+
+func TestCollectWatchDirsWalksNestedDirectories(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "src", "pkg", "sub")
+	err := os.MkdirAll(nested, 0o755)
+	assert.NilError(t, err)
+
+	dirs, err := collectWatchDirs(root, []string{"src/**/*.go"})
+	assert.NilError(t, err)
+
+	found := map[string]bool{}
+	for _, dir := range dirs {
+		found[dir] = true
+	}
+	assert.Assert(t, found[filepath.Join(root, "src")], "base directory should be watched")
+	assert.Assert(t, found[filepath.Join(root, "src", "pkg")], "nested directory should be watched")
+	assert.Assert(t, found[nested], "deeply nested directory should be watched")
+}